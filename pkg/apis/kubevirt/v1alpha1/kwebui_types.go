@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KWebUISpec defines the desired state of KWebUI
+type KWebUISpec struct {
+	// Version is the kubevirt-web-ui version to deploy, e.g. "1.4"; the image tag used is "v" + Version
+	Version string `json:"version"`
+	// RegistryUrl is the container registry hosting the kubevirt-web-ui image. Defaults to "quay.io"
+	RegistryUrl string `json:"registryUrl,omitempty"`
+	// RegistryNamespace is the registry namespace/org hosting the kubevirt-web-ui image. Defaults to "kubevirt"
+	RegistryNamespace string `json:"registryNamespace,omitempty"`
+	// OpenshiftMasterDefaultSubdomain overrides the cluster's default wildcard subdomain used for the console Route
+	OpenshiftMasterDefaultSubdomain string `json:"openshiftMasterDefaultSubdomain,omitempty"`
+	// PublicMasterHostname overrides the public hostname of the OpenShift master, used for OAuth redirect URIs
+	PublicMasterHostname string `json:"publicMasterHostname,omitempty"`
+}
+
+// KWebUIStatus defines the observed state of KWebUI
+type KWebUIStatus struct {
+	// Phase is a short machine-readable summary of the last reconcile outcome
+	Phase string `json:"phase,omitempty"`
+	// Message is a human-readable detail of the current Phase
+	Message string `json:"message,omitempty"`
+	// RetryCount is the number of consecutive failed provision/deprovision attempts
+	RetryCount int `json:"retryCount,omitempty"`
+	// LastAttemptTime is when the most recent provision/deprovision attempt was made
+	LastAttemptTime metav1.Time `json:"lastAttemptTime,omitempty"`
+	// NextRetryTime is when the controller will next retry a failed attempt, if any
+	NextRetryTime metav1.Time `json:"nextRetryTime,omitempty"`
+	// Conditions are the latest observations of the KWebUI's state
+	Conditions []KWebUICondition `json:"conditions,omitempty"`
+	// ObservedGeneration is the most recent instance.Generation the controller has acted on
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// DeployedVersion is the kubevirt-web-ui version currently running, as last confirmed by the controller
+	DeployedVersion string `json:"deployedVersion,omitempty"`
+}
+
+// KWebUICondition describes one aspect of the current state of a KWebUI, following the
+// condition conventions used throughout the Kubernetes API.
+type KWebUICondition struct {
+	// Type of the condition, e.g. DrainingSucceeded
+	Type string `json:"type"`
+	// Status is one of True, False, Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// Reason is a brief machine-readable explanation for the condition's last transition
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable detail of the last transition
+	Message string `json:"message,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KWebUI is the Schema for the kwebuis API
+// +k8s:openapi-gen=true
+type KWebUI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KWebUISpec   `json:"spec,omitempty"`
+	Status KWebUIStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KWebUIList contains a list of KWebUI
+type KWebUIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KWebUI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KWebUI{}, &KWebUIList{})
+}
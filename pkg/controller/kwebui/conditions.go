@@ -0,0 +1,79 @@
+package kwebui
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1alpha1 "kubevirt.io/web-ui-operator/pkg/apis/kubevirt/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Condition types reported on KWebUI.Status.Conditions. ConditionDrainingSucceeded lives in
+// drain.go alongside the drain loop that owns it.
+const (
+	ConditionProvisioned         = "Provisioned"
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	ConditionOAuthClientReady    = "OAuthClientReady"
+)
+
+// setCondition merges a condition by type into the KWebUI's status subresource, refreshing
+// LastTransitionTime only when the condition's Status actually changes. It skips the
+// Status().Update() entirely when the condition is already byte-for-byte identical to what's
+// stored: the controller watches KWebUI with a plain EnqueueRequestForObject, so every write
+// here bumps ResourceVersion and re-enqueues the object, and an unconditional write would turn
+// every reconcile of an at-rest KWebUI into a permanent hot-loop against itself.
+func (r *ReconcileKWebUI) setCondition(request reconcile.Request, condType string, status corev1.ConditionStatus, reason, message string) {
+	instance := &kubevirtv1alpha1.KWebUI{}
+	if err := r.client.Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		log.Error(err, "Failed to get KWebUI object to update condition", "Condition.Type", condType)
+		return
+	}
+
+	if !mergeCondition(instance, condType, status, reason, message) {
+		return
+	}
+
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, "Failed to update KWebUI condition", "Condition.Type", condType)
+	}
+}
+
+// mergeCondition updates instance.Status.Conditions in place, preserving LastTransitionTime
+// when the condition's Status is unchanged. It reports whether anything actually changed, so
+// setCondition can skip writing to the status subresource when there's nothing new to persist.
+func mergeCondition(instance *kubevirtv1alpha1.KWebUI, condType string, status corev1.ConditionStatus, reason, message string) bool {
+	now := metav1.Now()
+	for i := range instance.Status.Conditions {
+		c := &instance.Status.Conditions[i]
+		if c.Type != condType {
+			continue
+		}
+		if c.Status == status && c.Reason == reason && c.Message == message {
+			return false
+		}
+		if c.Status != status {
+			c.LastTransitionTime = now
+		}
+		c.Status = status
+		c.Reason = reason
+		c.Message = message
+		return true
+	}
+
+	instance.Status.Conditions = append(instance.Status.Conditions, kubevirtv1alpha1.KWebUICondition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+	return true
+}
+
+func conditionStatusFromBool(b bool) corev1.ConditionStatus {
+	if b {
+		return corev1.ConditionTrue
+	}
+	return corev1.ConditionFalse
+}
@@ -0,0 +1,35 @@
+package kwebui
+
+import (
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+)
+
+// routeGroupVersion and oauthGroupVersion are the API groups only present on OpenShift.
+const (
+	routeGroupVersion = "route.openshift.io/v1"
+	oauthGroupVersion = "oauth.openshift.io/v1"
+)
+
+// newDiscoveryClient builds a CachedDiscoveryClient from the in-cluster REST config so
+// repeated OpenShift-vs-Kubernetes checks don't each round-trip to the apiserver.
+func newDiscoveryClient(restConfig *rest.Config) (discovery.CachedDiscoveryInterface, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(discoveryClient), nil
+}
+
+// detectOpenShift reports whether the cluster serves the OpenShift-only route and oauth
+// APIs, so the controller knows whether to provision Route+OAuthClient or a plain Ingress.
+func detectOpenShift(disc discovery.DiscoveryInterface) bool {
+	if _, err := disc.ServerResourcesForGroupVersion(routeGroupVersion); err != nil {
+		return false
+	}
+	if _, err := disc.ServerResourcesForGroupVersion(oauthGroupVersion); err != nil {
+		return false
+	}
+	return true
+}
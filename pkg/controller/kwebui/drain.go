@@ -0,0 +1,134 @@
+package kwebui
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubevirtv1alpha1 "kubevirt.io/web-ui-operator/pkg/apis/kubevirt/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DeprovisionFinalizer is added to every KWebUI so Reconcile gets a chance to deprovision and
+// drain the console pods before the apiserver removes the CR, rather than relying on garbage
+// collection of the owned Deployment/Service/Route/ConfigMap/ServiceAccount.
+const DeprovisionFinalizer = "kubevirt.io/kwebui-deprovision"
+
+// ConditionDrainingSucceeded reports whether the console pods have finished terminating
+// after deprovisioning started, modeled after the cluster-api machine drain condition.
+const ConditionDrainingSucceeded = "DrainingSucceeded"
+
+const (
+	DrainPollInterval = 5 * time.Second
+	DrainTimeout      = 5 * time.Minute
+)
+
+// ensureFinalizer adds DeprovisionFinalizer to instance if it isn't already present.
+func (r *ReconcileKWebUI) ensureFinalizer(instance *kubevirtv1alpha1.KWebUI) (bool, error) {
+	if containsString(instance.Finalizers, DeprovisionFinalizer) {
+		return false, nil
+	}
+	instance.Finalizers = append(instance.Finalizers, DeprovisionFinalizer)
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// handleDeletion runs on a KWebUI with a non-nil DeletionTimestamp: it deprovisions the owned
+// objects, waits for the console pods to actually terminate, then removes the finalizer. It
+// never blocks the reconcile goroutine; while pods are still draining it requeues with a delay.
+func (r *ReconcileKWebUI) handleDeletion(request reconcile.Request, instance *kubevirtv1alpha1.KWebUI) (reconcile.Result, error) {
+	if !containsString(instance.Finalizers, DeprovisionFinalizer) {
+		// Nothing left for us to do; let the apiserver finish deleting the object.
+		return reconcile.Result{}, nil
+	}
+
+	if instance.Status.Phase != PhaseDeprovision && instance.Status.Phase != PhaseDeprovisioned {
+		res, err := deprovision(r, request, instance)
+		if err != nil {
+			return r.recordRetry(request, err, res)
+		}
+	}
+
+	if time.Since(instance.DeletionTimestamp.Time) > DrainTimeout {
+		log.Info("Drain timeout exceeded, removing finalizer without waiting further", "Request.NamespacedName", request.NamespacedName)
+		r.setCondition(request, ConditionDrainingSucceeded, corev1.ConditionFalse, "Timeout", "Timed out waiting for console pods to terminate")
+		return reconcile.Result{}, r.removeFinalizer(request)
+	}
+
+	drained, err := r.consolePodsDrained(request.Namespace)
+	if err != nil {
+		log.Error(err, "Failed to list console pods while draining", "Request.NamespacedName", request.NamespacedName)
+		return reconcile.Result{RequeueAfter: DrainPollInterval}, nil
+	}
+
+	if !drained {
+		r.setCondition(request, ConditionDrainingSucceeded, corev1.ConditionFalse, "Draining", "Waiting for console pods to terminate")
+		return reconcile.Result{RequeueAfter: DrainPollInterval}, nil
+	}
+
+	r.setCondition(request, ConditionDrainingSucceeded, corev1.ConditionTrue, "Drained", "All console pods terminated")
+	return reconcile.Result{}, r.removeFinalizer(request)
+}
+
+// removeFinalizer re-fetches the instance immediately before updating it, rather than reusing
+// the pointer handleDeletion fetched at the top of Reconcile: deprovision and the condition
+// writes above it each patch the status subresource through their own Get+Update, bumping
+// ResourceVersion on the server, so updating with the stale pointer would always hit a 409.
+func (r *ReconcileKWebUI) removeFinalizer(request reconcile.Request) error {
+	instance := &kubevirtv1alpha1.KWebUI{}
+	if err := r.client.Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		return err
+	}
+	instance.Finalizers = removeString(instance.Finalizers, DeprovisionFinalizer)
+	return r.client.Update(context.TODO(), instance)
+}
+
+// consolePodsDrained reports whether every console pod has reached zero ready replicas.
+func (r *ReconcileKWebUI) consolePodsDrained(namespace string) (bool, error) {
+	pods, err := r.clientset.CoreV1().Pods(namespace).List(metav1.ListOptions{LabelSelector: "app=" + WebUIContainerName})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	for i := range pods.Items {
+		if podReady(&pods.Items[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	result := list[:0]
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
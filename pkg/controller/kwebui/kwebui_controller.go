@@ -2,33 +2,30 @@ package kwebui
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
-	"io"
-	"os"
-	"os/exec"
 	"strings"
-	stderrors "errors"
-	"crypto/rand"
 
-    extenstionsv1beta1 "k8s.io/api/extensions/v1beta1"
-	"k8s.io/client-go/rest"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	extenstionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
 	kubevirtv1alpha1 "kubevirt.io/web-ui-operator/pkg/apis/kubevirt/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-const InventoryFilePattern = "/tmp/inventory_%s.ini"
-const ConfigFilePattern = "/tmp/config_%s"
-const PlaybookFile = "/kubevirt-web-ui-ansible/playbooks/kubevirt-web-ui/config.yml"
 const WebUIContainerName = "console"
 
 const PhaseFreshProvision = "PROVISION_STARTED"
@@ -39,19 +36,59 @@ const PhaseDeprovisioned = "DEPROVISIONED"
 const PhaseDeprovisionFailed = "DEPROVISION_FAILED"
 const PhaseOtherError = "OTHER_ERROR"
 const PhaseNoDeployment = "NOT_DEPLOYED"
-const PhaseOwnerReferenceFailed = "OWNER_REFERENCE_FAILED"
 
 var log = logf.Log.WithName("controller_kwebui")
 
 // Add creates a new KWebUI Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r, err := newReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return add(mgr, r)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileKWebUI{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	return NewReconciler(mgr, DefaultOptions())
+}
+
+// NewReconciler builds a ReconcileKWebUI with explicit Options instead of DefaultOptions. It
+// reads the in-cluster REST config, so it fails when run outside a cluster.
+func NewReconciler(mgr manager.Manager, opts Options) (reconcile.Reconciler, error) {
+	if opts.Clock == nil {
+		opts.Clock = realClock{}
+	}
+
+	restConfig, err := rest.InClusterConfig()
+	if err != nil {
+		log.Error(err, "Failed to get in-cluster config")
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		log.Error(err, "Failed to build typed clientset from in-cluster config")
+		return nil, err
+	}
+
+	discoveryClient, err := newDiscoveryClient(restConfig)
+	if err != nil {
+		log.Error(err, "Failed to build discovery client from in-cluster config")
+		return nil, err
+	}
+	isOpenShift := detectOpenShift(discoveryClient)
+	log.Info("Detected cluster flavor", "isOpenShift", isOpenShift)
+
+	return &ReconcileKWebUI{
+		client:      mgr.GetClient(),
+		scheme:      mgr.GetScheme(),
+		clientset:   clientset,
+		isOpenShift: isOpenShift,
+		opts:        opts,
+		limiter:     newRateLimiter(opts),
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -88,6 +125,15 @@ type ReconcileKWebUI struct {
 	// that reads objects from the cache and writes to the apiserver
 	client client.Client
 	scheme *runtime.Scheme
+	// clientset is a typed client built directly from the in-cluster REST config,
+	// used where the split client's cache isn't a good fit (e.g. polling Pod status).
+	clientset *kubernetes.Clientset
+	// isOpenShift is true when route.openshift.io and oauth.openshift.io are both served,
+	// determined once at startup; it decides whether to provision Route+OAuthClient or Ingress.
+	isOpenShift bool
+	// limiter bounds how fast a given KWebUI is retried after a failed provision/deprovision.
+	limiter workqueue.RateLimiter
+	opts    Options
 }
 
 // Reconcile reads that state of the cluster for a KWebUI object and makes changes based on the state read
@@ -96,10 +142,6 @@ type ReconcileKWebUI struct {
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileKWebUI) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	// TODO: in case of error wait before reconciling again, see
-	// following does not work: return reconcile.Result{RequeueAfter: RequeueDelay}, err
-	// for reason, see: vendor/sigs.k8s.io/controller-runtime/pkg/internal/controller/controller.go
-
 	reqLogger := log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqLogger.Info("Reconciling KWebUI")
 
@@ -109,22 +151,33 @@ func (r *ReconcileKWebUI) Reconcile(request reconcile.Request) (reconcile.Result
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
-			// TODO: use finalizer if the KWebUI CR is deleted
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
+
+	if instance.DeletionTimestamp != nil {
+		return r.handleDeletion(request, instance)
+	}
+
+	if added, err := r.ensureFinalizer(instance); err != nil {
+		return reconcile.Result{}, err
+	} else if added {
+		// The Update above will trigger a fresh reconcile; nothing more to do this round.
+		return reconcile.Result{}, nil
+	}
+
 	reqLogger.Info("Desired kubevirt-web-ui version: ", "instance.Spec.Version", instance.Spec.Version)
 
 	// Fetch the kubevirt-web-ui Deployment
 	deployment := &extenstionsv1beta1.Deployment{}
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: "console", Namespace: request.Namespace}, deployment)
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: WebUIContainerName, Namespace: request.Namespace}, deployment)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return freshProvision(r, request, instance)
+			res, provisionErr := freshProvision(r, request, instance)
+			return r.recordRetry(request, provisionErr, res)
 		}
 		reqLogger.Info("kubevirt-web-ui Deployment failed to be retrieved. Re-trying in a moment.", "error", err)
 		updateStatus(r, request, PhaseOtherError, "Failed to retrieve kubevirt-web-ui Deployment object.")
@@ -132,24 +185,8 @@ func (r *ReconcileKWebUI) Reconcile(request reconcile.Request) (reconcile.Result
 	}
 
 	// Deployment found
-	return reconcileExistingDeployment(r, request, instance, deployment)
-}
-
-func runPlaybookWithSetup(namespace string, instance *kubevirtv1alpha1.KWebUI, action string) (reconcile.Result, error) {
-	configFile, err := loginClient(namespace)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-	defer removeFile(configFile)
-
-	inventoryFile, err := generateInventory(instance, namespace, action)
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-	defer removeFile(inventoryFile)
-
-	err = runPlaybook(inventoryFile, configFile)
-	return reconcile.Result{}, err
+	res, reconcileErr := reconcileExistingDeployment(r, request, instance, deployment)
+	return r.recordRetry(request, reconcileErr, res)
 }
 
 func freshProvision(r *ReconcileKWebUI, request reconcile.Request, instance *kubevirtv1alpha1.KWebUI) (reconcile.Result, error) {
@@ -159,227 +196,200 @@ func freshProvision(r *ReconcileKWebUI, request reconcile.Request, instance *kub
 		return reconcile.Result{}, nil
 	}
 
-	// Kubevirt-web-ui deployment is not present yet
-	log.Info("kubevirt-web-ui Deployment is not present. Ansible playbook will be executed to provision it.")
+	log.Info("kubevirt-web-ui Deployment is not present. Provisioning it from in-memory object templates.")
 	updateStatus(r, request, PhaseFreshProvision, fmt.Sprintf("Target version: %s", instance.Spec.Version))
-	res, err := runPlaybookWithSetup(request.Namespace, instance, "provision")
-	if err == nil {
-		setOwnerReference(r, request, instance)
-		updateStatus(r, request, PhaseProvisioned, "Provision finished.")
-	} else {
+
+	if err := provisionObjects(r, request.Namespace, instance); err != nil {
+		r.setCondition(request, ConditionProvisioned, corev1.ConditionFalse, "ProvisionFailed", err.Error())
 		updateStatus(r, request, PhaseProvisionFailed, "Failed to provision Kubevirt Web UI. See operator's log for more details.")
+		return reconcile.Result{}, err
 	}
-	return res, err
+
+	r.setCondition(request, ConditionProvisioned, corev1.ConditionTrue, "ProvisionSucceeded", "All KWebUI objects created")
+	if r.isOpenShift {
+		r.setCondition(request, ConditionOAuthClientReady, corev1.ConditionTrue, "Created", "OAuthClient created")
+	}
+	updateProvisionedStatus(r, request, instance.Spec.Version, "Provision finished.")
+	return reconcile.Result{}, nil
 }
 
 func deprovision(r *ReconcileKWebUI, request reconcile.Request, instance *kubevirtv1alpha1.KWebUI) (reconcile.Result, error) {
 	log.Info("Existing kubevirt-web-ui deployment is about to be deprovisioned.")
 	updateStatus(r, request, PhaseDeprovision, "")
-	res, err := runPlaybookWithSetup(request.Namespace, instance, "deprovision")
-	if err == nil {
-		updateStatus(r, request, PhaseDeprovisioned, "Deprovision finished.")
-	} else {
+
+	if err := deprovisionObjects(r, request.Namespace, instance); err != nil {
 		updateStatus(r, request, PhaseDeprovisionFailed, "Failed to deprovision Kubevirt Web UI. See operator's log for more details.")
+		return reconcile.Result{}, err
 	}
 
-	return res, err
+	r.setCondition(request, ConditionProvisioned, corev1.ConditionFalse, "Deprovisioned", "KWebUI objects removed")
+	if r.isOpenShift {
+		r.setCondition(request, ConditionOAuthClientReady, corev1.ConditionFalse, "Deprovisioned", "OAuthClient removed")
+	}
+	updateStatus(r, request, PhaseDeprovisioned, "Deprovision finished.")
+	return reconcile.Result{}, nil
 }
 
-func reconcileExistingDeployment(r *ReconcileKWebUI, request reconcile.Request, instance *kubevirtv1alpha1.KWebUI, deployment *extenstionsv1beta1.Deployment) (reconcile.Result, error) {
-	existingVersion := ""
-	for _, container := range deployment.Spec.Template.Spec.Containers {
-		if container.Name == WebUIContainerName {
-			// quay.io/kubevirt/kubevirt-web-ui:v1.4
-			existingVersion = afterLast(container.Image, ":")
-			log.Info(fmt.Sprintf("Existing image tag: %s, from image: %s", existingVersion, container.Image))
-			existingVersion = strings.TrimPrefix(existingVersion, "v")
-			if existingVersion == "" {
-				log.Info("Failed to read existing image tag")
-				return reconcile.Result{}, stderrors.New("failed to read existing image tag")
-			}
-			break
-		}
+// provisionObjects creates the ServiceAccount, ConfigMap, Deployment and Service templates
+// rendered from instance.Spec, plus Route+OAuthClient on OpenShift or an Ingress on plain
+// Kubernetes, owner references already set at construction.
+func provisionObjects(r *ReconcileKWebUI, namespace string, instance *kubevirtv1alpha1.KWebUI) error {
+	sa, err := newServiceAccount(instance, namespace, r.scheme)
+	if err != nil {
+		return err
 	}
-
-	// TODO: reconcile based on other parameters, not only the Version
-
-	if existingVersion == "" {
-		log.Info("Can not read deployed container version, giving up.")
-		updateStatus(r, request, PhaseOtherError, "Can not read deployed container version.")
-		return reconcile.Result{}, nil
+	if err := createIfNotExists(r, sa); err != nil {
+		return err
 	}
 
-	if instance.Spec.Version == existingVersion {
-		msg := fmt.Sprintf("Existing version conform the requested one: %s. Nothing to do.", existingVersion)
-		log.Info(msg)
-		updateStatus(r, request, PhaseProvisioned, msg)
-		return reconcile.Result{}, nil
+	cm, err := newConfigMap(instance, namespace, r.scheme)
+	if err != nil {
+		return err
 	}
-
-	if instance.Spec.Version == "" { // deprovision only
-		return deprovision(r, request, instance)
+	if err := createIfNotExists(r, cm); err != nil {
+		return err
 	}
 
-	// requested and deployed version are different
-	// It should be enough to just re-execute the provision process and restart kubevirt-web-ui pod to read the updated ConfigMap.
-	// But deprovision is safer to address potential incompatible changes in the future.
-	_ , err := deprovision(r, request, instance)
+	dep, err := newDeployment(instance, namespace, r.scheme)
 	if err != nil {
-		log.Error(err, "Failed to deprovision existing deployment. Can not continue with provision of the requested one.")
-		return reconcile.Result{}, err
+		return err
+	}
+	if err := createIfNotExists(r, dep); err != nil {
+		return err
 	}
 
-	return freshProvision(r, request, instance)
-}
-
-func loginClient(namespace string) (string, error) {
-	config, err := rest.InClusterConfig()
+	svc, err := newService(instance, namespace, r.scheme)
 	if err != nil {
-		log.Error(err, fmt.Sprintf("Failed to get in-cluster config"))
-		return "", err
+		return err
 	}
-
-	configFile := fmt.Sprintf(ConfigFilePattern, unique())
-	env := []string{fmt.Sprintf("KUBECONFIG=%s", configFile)}
-
-	cmd, args := "oc", []string{
-		"login",
-		config.Host,
-		fmt.Sprintf("--certificate-authority=%s", config.TLSClientConfig.CAFile),
-		fmt.Sprintf("--token=%s", config.BearerToken),
+	if err := createIfNotExists(r, svc); err != nil {
+		return err
 	}
 
-	anonymArgs := append([]string{}, args...)
-	err = runCommand(cmd, args, env, anonymArgs)
-	if err != nil {
-		return "", err
+	if !r.isOpenShift {
+		ingress, err := newIngress(instance, namespace, r.scheme)
+		if err != nil {
+			return err
+		}
+		return createIfNotExists(r, ingress)
 	}
 
-	cmd, args = "oc", []string{
-		"project",
-		namespace,
-	}
-	err = runCommand(cmd, args, env, args)
+	route, err := newRoute(instance, namespace, r.scheme)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if err := createIfNotExists(r, route); err != nil {
+		return err
 	}
 
-	return configFile, nil
+	return createIfNotExists(r, newOAuthClient(instance, namespace))
 }
 
-func generateInventory(instance *kubevirtv1alpha1.KWebUI, namespace string, action string) (string, error) {
-	log.Info("Writing inventory file")
-	inventoryFile := fmt.Sprintf(InventoryFilePattern, unique())
-	f, err := os.Create(inventoryFile)
-	if err != nil {
-		log.Error(err, "Failed to write inventory file")
-		return "", err
-	}
-	defer f.Close()
+// deprovisionObjects removes the objects provisionObjects created, in reverse dependency order.
+func deprovisionObjects(r *ReconcileKWebUI, namespace string, instance *kubevirtv1alpha1.KWebUI) error {
+	if !r.isOpenShift {
+		ingress := &extenstionsv1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: WebUIContainerName, Namespace: namespace}}
+		if err := deleteIfExists(r, ingress); err != nil {
+			return err
+		}
+	} else {
+		if err := deleteIfExists(r, newOAuthClient(instance, namespace)); err != nil {
+			return err
+		}
 
-	f.WriteString("[OSEv3:children]\nmasters\n\n")
-	f.WriteString("[OSEv3:vars]\n")
-	f.WriteString("platform=openshift\n")
-	f.WriteString(strings.Join([]string{"apb_action=", action, "\n"}, ""))
-	f.WriteString(strings.Join([]string{"registry_url=", def(instance.Spec.RegistryUrl, "quay.io"), "\n"}, ""))
-	f.WriteString(strings.Join([]string{"registry_namespace=", def(instance.Spec.RegistryNamespace, "kubevirt"), "\n"}, ""))
-	f.WriteString(strings.Join([]string{"docker_tag=", def(instance.Spec.Version, "v1.4"), "\n"}, ""))
-	f.WriteString(strings.Join([]string{"kubevirt_web_ui_namespace=", def(namespace, "kubevirt-web-ui"), "\n"}, ""))
-	if action == "deprovision" {
-		f.WriteString("preserve_namespace=true\n")
+		route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: WebUIContainerName, Namespace: namespace}}
+		if err := deleteIfExists(r, route); err != nil {
+			return err
+		}
 	}
-	if instance.Spec.OpenshiftMasterDefaultSubdomain != "" {
-		f.WriteString(fmt.Sprintf("openshift_master_default_subdomain=%s\n", instance.Spec.OpenshiftMasterDefaultSubdomain))
+
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: WebUIContainerName, Namespace: namespace}}
+	if err := deleteIfExists(r, svc); err != nil {
+		return err
 	}
-	if instance.Spec.PublicMasterHostname != "" {
-		f.WriteString(fmt.Sprintf("public_master_hostname=%s\n", instance.Spec.PublicMasterHostname))
+
+	dep := &extenstionsv1beta1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: WebUIContainerName, Namespace: namespace}}
+	if err := deleteIfExists(r, dep); err != nil {
+		return err
 	}
-	f.WriteString("\n")
-	f.WriteString("[masters]\n")
-	_, err = f.WriteString("127.0.0.1 ansible_connection=local\n")
 
-	if err != nil {
-		log.Error(err, "Failed to write into the inventory file")
-		return "", err
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: WebUIContainerName, Namespace: namespace}}
+	if err := deleteIfExists(r, cm); err != nil {
+		return err
 	}
-	f.Sync()
-	log.Info("The inventory file is written.")
-	return inventoryFile, nil
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: WebUIContainerName, Namespace: namespace}}
+	return deleteIfExists(r, sa)
 }
 
-func setOwnerReference(r *ReconcileKWebUI, request reconcile.Request, instance *kubevirtv1alpha1.KWebUI) error {
-	deployment := &extenstionsv1beta1.Deployment{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: "console", Namespace: request.Namespace}, deployment)
-	if err != nil {
-		msg := "Failed to retrieve the just created kubevirt-web-ui Deployment object to set owner reference."
-		log.Error(err, msg)
-		updateStatus(r, request, PhaseOwnerReferenceFailed, msg)
+func createIfNotExists(r *ReconcileKWebUI, obj runtime.Object) error {
+	err := r.client.Create(context.TODO(), obj)
+	if err != nil && !errors.IsAlreadyExists(err) {
 		return err
 	}
+	return nil
+}
 
-	controllerutil.SetControllerReference(instance, deployment, r.scheme)
-	if err != nil {
-		msg := "Failed to set Operator CR as the owner of the kubevirt-web-ui Deployment object."
-		log.Error(err, msg)
-		updateStatus(r, request, PhaseOwnerReferenceFailed, msg)
+func deleteIfExists(r *ReconcileKWebUI, obj runtime.Object) error {
+	err := r.client.Delete(context.TODO(), obj)
+	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
-
 	return nil
 }
 
-func runPlaybook(inventoryFile, configFile string) error {
-	cmd, args := "ansible-playbook", []string{
-		"-i",
-		inventoryFile,
-		PlaybookFile,
-		"-vvv",
+func reconcileExistingDeployment(r *ReconcileKWebUI, request reconcile.Request, instance *kubevirtv1alpha1.KWebUI, deployment *extenstionsv1beta1.Deployment) (reconcile.Result, error) {
+	r.setCondition(request, ConditionDeploymentAvailable, conditionStatusFromBool(deployment.Status.AvailableReplicas > 0), "Observed", "Observed Deployment.Status.AvailableReplicas")
+
+	if instance.Generation == instance.Status.ObservedGeneration && instance.Status.Phase == PhaseProvisioned && instance.Status.DeployedVersion == instance.Spec.Version {
+		log.Info("No spec changes since last observed generation, nothing to do.")
+		return reconcile.Result{}, nil
 	}
-	env := []string{fmt.Sprintf("KUBECONFIG=%s", configFile)}
-	return runCommand(cmd, args, env, args)
-}
 
-func pipeToLog(pipe io.ReadCloser, name string) {
-	buf := make([]byte, 1024, 1024)
-	for {
-		n, err := pipe.Read(buf[:])
-		if n > 0 {
-			logPerLine(name, string(buf[:n]))
-		}
-		if err != nil {
-			if err != io.EOF {
-				log.Error(err,  fmt.Sprintf("%s read error", name))
-			}
-			return
+	existingVersion := ""
+	containerIndex := -1
+	for i, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == WebUIContainerName {
+			// quay.io/kubevirt/kubevirt-web-ui:v1.4
+			existingVersion = afterLast(container.Image, ":")
+			log.Info(fmt.Sprintf("Existing image tag: %s, from image: %s", existingVersion, container.Image))
+			existingVersion = strings.TrimPrefix(existingVersion, "v")
+			containerIndex = i
+			break
 		}
 	}
-}
 
-func runCommand(cmd string, args []string, env []string, anonymArgs []string) error {
-	command := exec.Command(cmd, args...)
-	command.Env = append(os.Environ(), env...)
-	stdoutIn,_ := command.StdoutPipe()
-	stderrIn,_ := command.StderrPipe()
+	// TODO: reconcile based on other parameters, not only the Version
 
-	err := command.Start()
-	if err != nil {
-		log.Error(err, fmt.Sprintf("Execution failed: %s %s", cmd, strings.Join(anonymArgs," ")))
-		return err
+	if existingVersion == "" {
+		log.Info("Can not read deployed container version, giving up.")
+		updateStatus(r, request, PhaseOtherError, "Can not read deployed container version.")
+		return reconcile.Result{}, stderrors.New("failed to read existing image tag")
 	}
-	go pipeToLog(stdoutIn, "stdout")
-	go pipeToLog(stderrIn, "stdout")
-	err = command.Wait()
-	if err != nil {
-		log.Error(err, fmt.Sprintf("Execution failed (wait): %s %s", cmd, strings.Join(anonymArgs," ")))
-		return err
+
+	if instance.Spec.Version == existingVersion {
+		msg := fmt.Sprintf("Existing version conform the requested one: %s. Nothing to do.", existingVersion)
+		log.Info(msg)
+		updateProvisionedStatus(r, request, existingVersion, msg)
+		return reconcile.Result{}, nil
 	}
-	return nil
-}
 
-func logPerLine(header string, out string) {
-	for _,line := range strings.Split(out, "\n") {
-		log.Info(fmt.Sprintf("%s: %s", header, line))
+	if instance.Spec.Version == "" { // deprovision only
+		return deprovision(r, request, instance)
+	}
+
+	// Requested and deployed version differ: patch the Deployment's image tag in place rather than
+	// deprovisioning and re-provisioning the whole stack. Kubernetes will roll the console pod to
+	// pick up the new image, and the pod re-reads the ConfigMap on startup.
+	log.Info(fmt.Sprintf("Patching kubevirt-web-ui Deployment from version %s to %s", existingVersion, instance.Spec.Version))
+	deployment.Spec.Template.Spec.Containers[containerIndex].Image = buildImage(instance)
+	if err := r.client.Update(context.TODO(), deployment); err != nil {
+		updateStatus(r, request, PhaseOtherError, "Failed to patch kubevirt-web-ui Deployment image.")
+		return reconcile.Result{}, err
 	}
+
+	updateProvisionedStatus(r, request, instance.Spec.Version, fmt.Sprintf("Patched to version: %s", instance.Spec.Version))
+	return reconcile.Result{}, nil
 }
 
 func def(s string, defVal string) string {
@@ -389,13 +399,6 @@ func def(s string, defVal string) string {
 	return s
 }
 
-func removeFile(name string) {
-	err := os.Remove(name)
-	if err != nil {
-		log.Error(err, fmt.Sprintf("Failed to remove file: %s", name))
-	}
-}
-
 func afterLast(value string, a string) string {
 	pos := strings.LastIndex(value, a)
 	if pos == -1 {
@@ -408,6 +411,9 @@ func afterLast(value string, a string) string {
 	return value[adjustedPos:]
 }
 
+// updateStatus patches the /status subresource with the given Phase and Message, and records
+// that this reconcile observed instance.Generation. It does not touch DeployedVersion or
+// Conditions; use updateProvisionedStatus when a provision/patch attempt succeeded.
 func updateStatus(r *ReconcileKWebUI, request reconcile.Request, phase string, msg string) {
 	instance := &kubevirtv1alpha1.KWebUI{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
@@ -418,16 +424,29 @@ func updateStatus(r *ReconcileKWebUI, request reconcile.Request, phase string, m
 
 	instance.Status.Phase = phase
 	instance.Status.Message = msg
-	err = r.client.Update(context.TODO(), instance)
+	instance.Status.ObservedGeneration = instance.Generation
+	err = r.client.Status().Update(context.TODO(), instance)
 	if err != nil {
 		log.Error(err, fmt.Sprintf("Failed to update KWebUI status. Intended to write phase: '%s', message: %s", phase, msg))
 	}
 }
 
-func unique() string {
-	b := make([]byte, 5)
-	if _, err := rand.Read(b); err != nil {
-		return "abcde"
+// updateProvisionedStatus records that version is now the deployed version, alongside the
+// usual Phase/Message/ObservedGeneration bookkeeping updateStatus performs.
+func updateProvisionedStatus(r *ReconcileKWebUI, request reconcile.Request, version string, msg string) {
+	instance := &kubevirtv1alpha1.KWebUI{}
+	err := r.client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Failed to get KWebUI object to update status info. Intended to write phase: '%s', message: %s", PhaseProvisioned, msg))
+		return
 	}
-	return fmt.Sprintf("%X", b)
-}
\ No newline at end of file
+
+	instance.Status.Phase = PhaseProvisioned
+	instance.Status.Message = msg
+	instance.Status.DeployedVersion = version
+	instance.Status.ObservedGeneration = instance.Generation
+	err = r.client.Status().Update(context.TODO(), instance)
+	if err != nil {
+		log.Error(err, fmt.Sprintf("Failed to update KWebUI status. Intended to write phase: '%s', message: %s", PhaseProvisioned, msg))
+	}
+}
@@ -0,0 +1,218 @@
+package kwebui
+
+import (
+	"fmt"
+
+	oauthv1 "github.com/openshift/api/oauth/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	extenstionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubevirtv1alpha1 "kubevirt.io/web-ui-operator/pkg/apis/kubevirt/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const ConsolePort = 8443
+
+// buildImage renders the kubevirt-web-ui container image reference for the
+// requested Spec, falling back to the same defaults the ansible inventory used to apply.
+func buildImage(instance *kubevirtv1alpha1.KWebUI) string {
+	registryURL := def(instance.Spec.RegistryUrl, "quay.io")
+	registryNamespace := def(instance.Spec.RegistryNamespace, "kubevirt")
+	version := def(instance.Spec.Version, "1.4")
+	return fmt.Sprintf("%s/%s/kubevirt-web-ui:v%s", registryURL, registryNamespace, version)
+}
+
+// consoleLabels returns the label set applied to every object owned by a KWebUI instance.
+func consoleLabels() map[string]string {
+	return map[string]string{"app": WebUIContainerName}
+}
+
+func newServiceAccount(instance *kubevirtv1alpha1.KWebUI, namespace string, scheme *runtime.Scheme) (*corev1.ServiceAccount, error) {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebUIContainerName,
+			Namespace: namespace,
+			Labels:    consoleLabels(),
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, sa, scheme); err != nil {
+		return nil, err
+	}
+	return sa, nil
+}
+
+func newConfigMap(instance *kubevirtv1alpha1.KWebUI, namespace string, scheme *runtime.Scheme) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebUIContainerName,
+			Namespace: namespace,
+			Labels:    consoleLabels(),
+		},
+		Data: map[string]string{
+			"openshift_master_default_subdomain": instance.Spec.OpenshiftMasterDefaultSubdomain,
+			"public_master_hostname":             instance.Spec.PublicMasterHostname,
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, cm, scheme); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+func newDeployment(instance *kubevirtv1alpha1.KWebUI, namespace string, scheme *runtime.Scheme) (*extenstionsv1beta1.Deployment, error) {
+	replicas := int32(1)
+	dep := &extenstionsv1beta1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebUIContainerName,
+			Namespace: namespace,
+			Labels:    consoleLabels(),
+		},
+		Spec: extenstionsv1beta1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: consoleLabels()},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: WebUIContainerName,
+					Containers: []corev1.Container{
+						{
+							Name:  WebUIContainerName,
+							Image: buildImage(instance),
+							Ports: []corev1.ContainerPort{
+								{ContainerPort: ConsolePort},
+							},
+							EnvFrom: []corev1.EnvFromSource{
+								{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: WebUIContainerName}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, dep, scheme); err != nil {
+		return nil, err
+	}
+	return dep, nil
+}
+
+func newService(instance *kubevirtv1alpha1.KWebUI, namespace string, scheme *runtime.Scheme) (*corev1.Service, error) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebUIContainerName,
+			Namespace: namespace,
+			Labels:    consoleLabels(),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: consoleLabels(),
+			Ports: []corev1.ServicePort{
+				{Name: "https", Port: ConsolePort, TargetPort: intstr.FromInt(ConsolePort)},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, svc, scheme); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+func newRoute(instance *kubevirtv1alpha1.KWebUI, namespace string, scheme *runtime.Scheme) (*routev1.Route, error) {
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebUIContainerName,
+			Namespace: namespace,
+			Labels:    consoleLabels(),
+		},
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: WebUIContainerName,
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: intstr.FromInt(ConsolePort),
+			},
+			TLS: &routev1.TLSConfig{
+				Termination: routev1.TLSTerminationPassthrough,
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, route, scheme); err != nil {
+		return nil, err
+	}
+	return route, nil
+}
+
+// routeHost returns the hostname a Route will be reachable on once admitted, assuming a
+// convention of "<name>-<namespace>.<default-subdomain>" when no explicit host is set.
+func routeHost(instance *kubevirtv1alpha1.KWebUI, namespace string) string {
+	if instance.Spec.PublicMasterHostname != "" {
+		return instance.Spec.PublicMasterHostname
+	}
+	return fmt.Sprintf("%s-%s.%s", WebUIContainerName, namespace, instance.Spec.OpenshiftMasterDefaultSubdomain)
+}
+
+// newOAuthClient builds the cluster-scoped OAuthClient used for console login.
+// OAuthClient has no namespace, so it cannot carry an owner reference to the namespaced
+// KWebUI instance; deprovision deletes it explicitly by name instead.
+func newOAuthClient(instance *kubevirtv1alpha1.KWebUI, namespace string) *oauthv1.OAuthClient {
+	return &oauthv1.OAuthClient{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   oauthClientName(namespace),
+			Labels: consoleLabels(),
+		},
+		RespondWithChallenges: false,
+		RedirectURIs:          []string{fmt.Sprintf("https://%s", routeHost(instance, namespace))},
+		GrantMethod:           oauthv1.GrantHandlerAuto,
+	}
+}
+
+func oauthClientName(namespace string) string {
+	return fmt.Sprintf("%s-%s", WebUIContainerName, namespace)
+}
+
+// ingressHost returns the hostname to request for the console Ingress. Unlike routeHost,
+// it never falls back to the OpenShift-only "<name>-<namespace>.<default-subdomain>"
+// convention: OpenshiftMasterDefaultSubdomain has no equivalent on plain Kubernetes, and
+// synthesizing a host from an empty subdomain produces an invalid, dot-terminated DNS1123
+// name that the apiserver rejects. With no explicit PublicMasterHostname the rule is left
+// host-less, which ingress controllers treat as matching any inbound host.
+func ingressHost(instance *kubevirtv1alpha1.KWebUI) string {
+	return instance.Spec.PublicMasterHostname
+}
+
+// newIngress builds the Ingress used to expose the console Service on plain Kubernetes
+// clusters, where Route and OAuthClient aren't available.
+func newIngress(instance *kubevirtv1alpha1.KWebUI, namespace string, scheme *runtime.Scheme) (*extenstionsv1beta1.Ingress, error) {
+	ingress := &extenstionsv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WebUIContainerName,
+			Namespace: namespace,
+			Labels:    consoleLabels(),
+		},
+		Spec: extenstionsv1beta1.IngressSpec{
+			Rules: []extenstionsv1beta1.IngressRule{
+				{
+					Host: ingressHost(instance),
+					IngressRuleValue: extenstionsv1beta1.IngressRuleValue{
+						HTTP: &extenstionsv1beta1.HTTPIngressRuleValue{
+							Paths: []extenstionsv1beta1.HTTPIngressPath{
+								{
+									Backend: extenstionsv1beta1.IngressBackend{
+										ServiceName: WebUIContainerName,
+										ServicePort: intstr.FromInt(ConsolePort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, ingress, scheme); err != nil {
+		return nil, err
+	}
+	return ingress, nil
+}
@@ -0,0 +1,152 @@
+package kwebui
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	kubevirtv1alpha1 "kubevirt.io/web-ui-operator/pkg/apis/kubevirt/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// DefaultRetryQPS/DefaultRetryBurst bound how often this controller will hammer the apiserver
+	// (or oc/ansible, historically) while retrying a failing KWebUI.
+	DefaultRetryQPS   = 1
+	DefaultRetryBurst = 10
+
+	DefaultBaseBackoff = 5 * time.Second
+	DefaultMaxBackoff  = 5 * time.Minute
+	DefaultMaxRetries  = 8
+)
+
+// Clock abstracts time.Now so tests can inject a fake clock when asserting retry/backoff
+// bookkeeping (LastAttemptTime, NextRetryTime) without sleeping or racing the wall clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock is the Clock used in production.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Options configures the retry/rate-limiting behavior of the KWebUI controller.
+type Options struct {
+	RetryQPS    int
+	RetryBurst  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	MaxRetries  int
+	// Clock defaults to the wall clock; tests construct their own Options with a fake Clock
+	// and set them on a ReconcileKWebUI directly to assert backoff math deterministically.
+	Clock Clock
+}
+
+// DefaultOptions returns the Options used by Add.
+func DefaultOptions() Options {
+	return Options{
+		RetryQPS:    DefaultRetryQPS,
+		RetryBurst:  DefaultRetryBurst,
+		BaseBackoff: DefaultBaseBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		MaxRetries:  DefaultMaxRetries,
+		Clock:       realClock{},
+	}
+}
+
+// newRateLimiter combines a token-bucket limiter (overall request rate) with a per-item
+// exponential-backoff limiter (how fast one failing KWebUI is retried), the same composition
+// client-go's workqueue uses for its default controller rate limiter.
+func newRateLimiter(opts Options) workqueue.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(opts.BaseBackoff, opts.MaxBackoff),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(opts.RetryQPS), opts.RetryBurst)},
+	)
+}
+
+// isFatal reports whether err should abort retries outright rather than being requeued, e.g.
+// a malformed in-cluster configuration that no amount of backoff will fix.
+func isFatal(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.IsBadRequest(err), errors.IsInvalid(err), errors.IsForbidden(err), errors.IsUnauthorized(err):
+		return true
+	default:
+		return false
+	}
+}
+
+// recordRetry translates the outcome of a freshProvision/deprovision attempt into the
+// reconcile.Result to return, updating KWebUI.Status retry bookkeeping along the way. Returning
+// a nil error with RequeueAfter set (rather than the error itself) matters: the controller
+// ignores RequeueAfter whenever Reconcile also returns a non-nil error.
+func (r *ReconcileKWebUI) recordRetry(request reconcile.Request, err error, res reconcile.Result) (reconcile.Result, error) {
+	key := request.NamespacedName
+	if err == nil {
+		r.limiter.Forget(key)
+		r.resetRetryStatus(request)
+		return res, nil
+	}
+
+	if isFatal(err) {
+		r.limiter.Forget(key)
+		log.Error(err, "Fatal error, giving up without requeue", "Request.NamespacedName", key)
+		return reconcile.Result{}, nil
+	}
+
+	retryCount := r.limiter.NumRequeues(key) + 1
+	if retryCount > r.opts.MaxRetries {
+		r.limiter.Forget(key)
+		log.Error(err, "Max retries exceeded, giving up", "Request.NamespacedName", key, "retryCount", retryCount)
+		r.updateRetryStatus(request, retryCount, time.Time{})
+		return reconcile.Result{}, nil
+	}
+
+	backoff := r.limiter.When(key)
+	nextRetry := r.opts.Clock.Now().Add(backoff)
+	r.updateRetryStatus(request, retryCount, nextRetry)
+	log.Info("Requeuing after transient failure", "Request.NamespacedName", key, "retryCount", retryCount, "backoff", backoff)
+	return reconcile.Result{RequeueAfter: backoff}, nil
+}
+
+func (r *ReconcileKWebUI) updateRetryStatus(request reconcile.Request, retryCount int, nextRetry time.Time) {
+	instance := &kubevirtv1alpha1.KWebUI{}
+	if err := r.client.Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		log.Error(err, "Failed to get KWebUI object to update retry status")
+		return
+	}
+
+	instance.Status.RetryCount = retryCount
+	instance.Status.LastAttemptTime = metav1.NewTime(r.opts.Clock.Now())
+	if nextRetry.IsZero() {
+		instance.Status.NextRetryTime = metav1.Time{}
+	} else {
+		instance.Status.NextRetryTime = metav1.NewTime(nextRetry)
+	}
+
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, "Failed to update KWebUI retry status")
+	}
+}
+
+func (r *ReconcileKWebUI) resetRetryStatus(request reconcile.Request) {
+	instance := &kubevirtv1alpha1.KWebUI{}
+	if err := r.client.Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		log.Error(err, "Failed to get KWebUI object to reset retry status")
+		return
+	}
+	if instance.Status.RetryCount == 0 {
+		return
+	}
+
+	instance.Status.RetryCount = 0
+	instance.Status.NextRetryTime = metav1.Time{}
+	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
+		log.Error(err, "Failed to reset KWebUI retry status")
+	}
+}
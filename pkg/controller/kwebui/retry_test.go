@@ -0,0 +1,108 @@
+package kwebui
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kubevirtv1alpha1 "kubevirt.io/web-ui-operator/pkg/apis/kubevirt/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fakeClock is a Clock with a value tests can advance explicitly, instead of sleeping
+// or racing the wall clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestIsFatal(t *testing.T) {
+	gr := schema.GroupResource{Group: "kubevirt.io", Resource: "kwebuis"}
+	cases := []struct {
+		name  string
+		err   error
+		fatal bool
+	}{
+		{"nil", nil, false},
+		{"forbidden", errors.NewForbidden(gr, "console", nil), true},
+		{"unauthorized", errors.NewUnauthorized("no creds"), true},
+		{"invalid", errors.NewInvalid(schema.GroupKind{Group: "kubevirt.io", Kind: "KWebUI"}, "console", nil), true},
+		{"bad request", errors.NewBadRequest("malformed"), true},
+		{"server timeout (retriable)", errors.NewServerTimeout(gr, "get", 1), false},
+		{"plain error (retriable)", stderrors.New("failed to read existing image tag"), false},
+	}
+
+	for _, tc := range cases {
+		if got := isFatal(tc.err); got != tc.fatal {
+			t.Errorf("isFatal(%s) = %v, want %v", tc.name, got, tc.fatal)
+		}
+	}
+}
+
+func TestNewRateLimiterBacksOffExponentially(t *testing.T) {
+	opts := Options{RetryQPS: 100, RetryBurst: 100, BaseBackoff: 1 * time.Second, MaxBackoff: 1 * time.Minute, MaxRetries: DefaultMaxRetries}
+	limiter := newRateLimiter(opts)
+
+	key := "default/console"
+	first := limiter.When(key)
+	second := limiter.When(key)
+	if second <= first {
+		t.Fatalf("expected backoff to increase on repeated failures, got first=%v second=%v", first, second)
+	}
+
+	limiter.Forget(key)
+	if n := limiter.NumRequeues(key); n != 0 {
+		t.Fatalf("expected NumRequeues to reset to 0 after Forget, got %d", n)
+	}
+}
+
+func TestRecordRetryUsesInjectedClock(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := kubevirtv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to register KWebUI with scheme: %v", err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "console", Namespace: "default"}
+	instance := &kubevirtv1alpha1.KWebUI{
+		ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace},
+	}
+	fakeClient := fake.NewFakeClientWithScheme(scheme, instance)
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	opts := Options{
+		RetryQPS:    100,
+		RetryBurst:  100,
+		BaseBackoff: 1 * time.Second,
+		MaxBackoff:  1 * time.Minute,
+		MaxRetries:  DefaultMaxRetries,
+		Clock:       clock,
+	}
+	r := &ReconcileKWebUI{client: fakeClient, opts: opts, limiter: newRateLimiter(opts)}
+	request := reconcile.Request{NamespacedName: namespacedName}
+
+	if _, err := r.recordRetry(request, stderrors.New("transient failure"), reconcile.Result{}); err != nil {
+		t.Fatalf("recordRetry returned unexpected error: %v", err)
+	}
+
+	updated := &kubevirtv1alpha1.KWebUI{}
+	if err := fakeClient.Get(context.TODO(), namespacedName, updated); err != nil {
+		t.Fatalf("failed to get updated KWebUI: %v", err)
+	}
+
+	if !updated.Status.LastAttemptTime.Time.Equal(clock.now) {
+		t.Errorf("LastAttemptTime = %v, want %v (the fake clock's current time)", updated.Status.LastAttemptTime.Time, clock.now)
+	}
+
+	wantNextRetry := clock.now.Add(opts.BaseBackoff)
+	if !updated.Status.NextRetryTime.Time.Equal(wantNextRetry) {
+		t.Errorf("NextRetryTime = %v, want %v (fake clock's time + base backoff)", updated.Status.NextRetryTime.Time, wantNextRetry)
+	}
+}